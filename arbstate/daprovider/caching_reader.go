@@ -0,0 +1,158 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+var (
+	cachingReaderHitCounter      = metrics.NewRegisteredCounter("arb/daprovider/cachingreader/hit", nil)
+	cachingReaderMissCounter     = metrics.NewRegisteredCounter("arb/daprovider/cachingreader/miss", nil)
+	cachingReaderEvictionCounter = metrics.NewRegisteredCounter("arb/daprovider/cachingreader/eviction", nil)
+)
+
+// negativeResultTTL bounds how long a failed RecoverPayloadFromBatch call is cached for, so a
+// temporarily broken DA endpoint isn't hammered by repeated reorg/validation replays but is
+// retried reasonably soon.
+const negativeResultTTL = 10 * time.Second
+
+type cachingReaderKey [8 + 32 + 32]byte
+
+func makeCachingReaderKey(batchNum uint64, batchBlockHash common.Hash, sequencerMsg []byte) cachingReaderKey {
+	var key cachingReaderKey
+	binary.BigEndian.PutUint64(key[:8], batchNum)
+	copy(key[8:40], batchBlockHash[:])
+	msgHash := sha256.Sum256(sequencerMsg)
+	copy(key[40:], msgHash[:])
+	return key
+}
+
+type cachingReaderEntry struct {
+	payload   []byte
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte
+	err       error
+	cachedAt  time.Time
+}
+
+// CachingReader wraps a Reader with a bounded LRU of recovered payloads, keyed by
+// (batchNum, batchBlockHash, sha256(sequencerMsg)), so repeated re-fetches of the same batch
+// during reorgs or validator re-execution don't re-hit the underlying DA source. Negative
+// results (errors) are cached too, but only for negativeResultTTL, so a broken DA endpoint
+// isn't hammered on every replay. Eviction is bounded both by entry count and by total cached
+// payload bytes.
+//
+// preimages is an output parameter of Reader.RecoverPayloadFromBatch (the DAS path populates it
+// in place as it recovers the payload), so a cache miss snapshots the caller's preimages map as it
+// stands after the call (including any entries it already held beforehand), and a cache hit
+// merges that snapshot into the caller's map.
+type CachingReader struct {
+	reader     Reader
+	cache      *lru.Cache[cachingReaderKey, *cachingReaderEntry]
+	maxBytes   int64
+	totalBytes int64
+	mu         sync.Mutex
+}
+
+// NewCachingReader wraps reader with an LRU cache holding up to maxEntries recovered payloads
+// and at most maxBytes of cumulative payload data.
+func NewCachingReader(reader Reader, maxEntries int, maxBytes int64) (*CachingReader, error) {
+	c := &CachingReader{reader: reader, maxBytes: maxBytes}
+	cache, err := lru.NewWithEvict[cachingReaderKey, *cachingReaderEntry](maxEntries, func(_ cachingReaderKey, entry *cachingReaderEntry) {
+		atomic.AddInt64(&c.totalBytes, -int64(len(entry.payload)))
+		cachingReaderEvictionCounter.Inc(1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cache = cache
+	return c, nil
+}
+
+func (c *CachingReader) IsValidHeaderByte(headerByte byte) bool {
+	return c.reader.IsValidHeaderByte(headerByte)
+}
+
+func (c *CachingReader) RecoverPayloadFromBatch(
+	ctx context.Context,
+	batchNum uint64,
+	batchBlockHash common.Hash,
+	sequencerMsg []byte,
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
+	keysetValidationMode KeysetValidationMode,
+) ([]byte, error) {
+	key := makeCachingReaderKey(batchNum, batchBlockHash, sequencerMsg)
+	if entry, ok := c.cache.Get(key); ok {
+		if entry.err == nil || time.Since(entry.cachedAt) < negativeResultTTL {
+			cachingReaderHitCounter.Inc(1)
+			mergePreimagesInto(preimages, entry.preimages)
+			return entry.payload, entry.err
+		}
+		c.cache.Remove(key)
+	}
+	cachingReaderMissCounter.Inc(1)
+	payload, err := c.reader.RecoverPayloadFromBatch(ctx, batchNum, batchBlockHash, sequencerMsg, preimages, keysetValidationMode)
+	c.add(key, &cachingReaderEntry{payload: payload, preimages: clonePreimages(preimages), err: err, cachedAt: time.Now()})
+	return payload, err
+}
+
+// clonePreimages takes a snapshot of the whole preimages map, so a later cache hit can replay it
+// (what the original RecoverPayloadFromBatch call populated, plus whatever the map held before
+// that call), independent of what the caller does with its own map afterwards.
+func clonePreimages(preimages map[arbutil.PreimageType]map[common.Hash][]byte) map[arbutil.PreimageType]map[common.Hash][]byte {
+	if preimages == nil {
+		return nil
+	}
+	cloned := make(map[arbutil.PreimageType]map[common.Hash][]byte, len(preimages))
+	for preimageType, byHash := range preimages {
+		clonedByHash := make(map[common.Hash][]byte, len(byHash))
+		for hash, preimage := range byHash {
+			clonedByHash[hash] = preimage
+		}
+		cloned[preimageType] = clonedByHash
+	}
+	return cloned
+}
+
+// mergePreimagesInto copies src into dest, the way the underlying reader would have populated
+// dest directly on a cache miss.
+func mergePreimagesInto(dest, src map[arbutil.PreimageType]map[common.Hash][]byte) {
+	if dest == nil || src == nil {
+		return
+	}
+	for preimageType, byHash := range src {
+		destByHash, ok := dest[preimageType]
+		if !ok {
+			destByHash = make(map[common.Hash][]byte, len(byHash))
+			dest[preimageType] = destByHash
+		}
+		for hash, preimage := range byHash {
+			destByHash[hash] = preimage
+		}
+	}
+}
+
+// add inserts entry into the cache and evicts the least recently used entries, beyond whatever
+// lru.Cache's own maxEntries eviction already did, until total cached payload bytes fit within
+// maxBytes.
+func (c *CachingReader) add(key cachingReaderKey, entry *cachingReaderEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Add(key, entry)
+	atomic.AddInt64(&c.totalBytes, int64(len(entry.payload)))
+	for atomic.LoadInt64(&c.totalBytes) > c.maxBytes && c.cache.Len() > 0 {
+		c.cache.RemoveOldest()
+	}
+}
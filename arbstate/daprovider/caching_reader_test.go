@@ -0,0 +1,108 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type countingReader struct {
+	calls     int
+	payload   []byte
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte
+	err       error
+}
+
+func (r *countingReader) IsValidHeaderByte(byte) bool { return true }
+
+func (r *countingReader) RecoverPayloadFromBatch(
+	_ context.Context,
+	_ uint64,
+	_ common.Hash,
+	_ []byte,
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
+	_ KeysetValidationMode,
+) ([]byte, error) {
+	r.calls++
+	for preimageType, byHash := range r.preimages {
+		dest, ok := preimages[preimageType]
+		if !ok {
+			dest = make(map[common.Hash][]byte)
+			preimages[preimageType] = dest
+		}
+		for hash, preimage := range byHash {
+			dest[hash] = preimage
+		}
+	}
+	return r.payload, r.err
+}
+
+func TestCachingReaderHitsAvoidUnderlyingCallsAndReplayPreimages(t *testing.T) {
+	preimageHash := common.HexToHash("0x01")
+	underlying := &countingReader{
+		payload:   []byte("payload"),
+		preimages: map[arbutil.PreimageType]map[common.Hash][]byte{0: {preimageHash: []byte("preimage")}},
+	}
+	cache, err := NewCachingReader(underlying, 10, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create CachingReader: %v", err)
+	}
+
+	sequencerMsg := []byte("batch")
+
+	firstPreimages := make(map[arbutil.PreimageType]map[common.Hash][]byte)
+	payload, err := cache.RecoverPayloadFromBatch(context.Background(), 1, common.Hash{}, sequencerMsg, firstPreimages, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != "payload" || underlying.calls != 1 {
+		t.Fatalf("expected a miss to call the underlying reader once, got calls=%d payload=%q", underlying.calls, payload)
+	}
+	if firstPreimages[0][preimageHash] == nil {
+		t.Fatalf("expected preimages to be populated on a miss")
+	}
+
+	secondPreimages := make(map[arbutil.PreimageType]map[common.Hash][]byte)
+	payload, err = cache.RecoverPayloadFromBatch(context.Background(), 1, common.Hash{}, sequencerMsg, secondPreimages, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != "payload" || underlying.calls != 1 {
+		t.Fatalf("expected a hit to skip the underlying reader, got calls=%d", underlying.calls)
+	}
+	if string(secondPreimages[0][preimageHash]) != "preimage" {
+		t.Fatalf("expected a cache hit to replay the preimages the original call produced, got %v", secondPreimages)
+	}
+}
+
+func TestCachingReaderEvictsLeastRecentlyUsedEntry(t *testing.T) {
+	underlying := &countingReader{payload: []byte("x")}
+	cache, err := NewCachingReader(underlying, 1, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create CachingReader: %v", err)
+	}
+
+	for i := uint64(0); i < 2; i++ {
+		msg := []byte(fmt.Sprintf("batch-%d", i))
+		if _, err := cache.RecoverPayloadFromBatch(context.Background(), i, common.Hash{}, msg, make(map[arbutil.PreimageType]map[common.Hash][]byte), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if underlying.calls != 2 {
+		t.Fatalf("expected two distinct batches to both miss, got calls=%d", underlying.calls)
+	}
+
+	// The first batch should have been evicted once the second one was cached (maxEntries=1).
+	if _, err := cache.RecoverPayloadFromBatch(context.Background(), 0, common.Hash{}, []byte("batch-0"), make(map[arbutil.PreimageType]map[common.Hash][]byte), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if underlying.calls != 3 {
+		t.Fatalf("expected the evicted entry to miss again, got calls=%d", underlying.calls)
+	}
+}
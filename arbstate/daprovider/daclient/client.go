@@ -0,0 +1,206 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// Package daclient lets a third-party DA provider run as an independent binary and be wired
+// into nitro over gRPC, rather than being compiled into the node. See daprovider.proto for the
+// wire format.
+package daclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// chunkSize bounds how much of a sequencer message, recovered payload, or stored batch is sent
+// in a single gRPC message, so large payloads don't exceed gRPC's default message size limit.
+const chunkSize = 3 << 20 // 3 MiB
+
+// NewReaderForRPC is generally meant to be only used by nitro.
+// DA Providers should implement the DAProviderService gRPC service independently.
+func NewReaderForRPC(conn *grpc.ClientConn) daprovider.Reader {
+	return &readerForRPC{client: NewDAProviderServiceClient(conn)}
+}
+
+type readerForRPC struct {
+	client DAProviderServiceClient
+}
+
+func (r *readerForRPC) IsValidHeaderByte(headerByte byte) bool {
+	reply, err := r.client.IsValidHeaderByte(context.Background(), &IsValidHeaderByteRequest{HeaderByte: uint32(headerByte)})
+	if err != nil {
+		return false
+	}
+	return reply.IsValid
+}
+
+func (r *readerForRPC) RecoverPayloadFromBatch(
+	ctx context.Context,
+	batchNum uint64,
+	batchBlockHash common.Hash,
+	sequencerMsg []byte,
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
+	keysetValidationMode daprovider.KeysetValidationMode,
+) ([]byte, error) {
+	stream, err := r.client.RecoverPayloadFromBatch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RecoverPayloadFromBatch stream: %w", err)
+	}
+	chunks := chunkBytes(sequencerMsg)
+	if len(chunks) == 0 {
+		chunks = [][]byte{nil}
+	}
+	for i, chunk := range chunks {
+		req := &RecoverPayloadFromBatchRequest{SequencerMsgChunk: chunk}
+		if i == 0 {
+			req.BatchNum = batchNum
+			req.BatchBlockHash = batchBlockHash[:]
+			req.Preimages = encodePreimages(preimages)
+			req.KeysetValidationMode = uint32(keysetValidationMode)
+		}
+		if err := stream.Send(req); err != nil {
+			return nil, fmt.Errorf("failed to send RecoverPayloadFromBatch request chunk: %w", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("failed to close RecoverPayloadFromBatch send stream: %w", err)
+	}
+	var payload []byte
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive RecoverPayloadFromBatch reply chunk: %w", err)
+		}
+		payload = append(payload, reply.PayloadChunk...)
+		mergePreimages(preimages, reply.Preimages)
+	}
+	return payload, nil
+}
+
+// mergePreimages copies the preimages the server reported back into the caller-supplied
+// preimages map, matching how the in-process DAS readers populate it in place.
+func mergePreimages(dest map[arbutil.PreimageType]map[common.Hash][]byte, src *Preimages) {
+	if dest == nil || src == nil {
+		return
+	}
+	for preimageType, byHash := range decodePreimages(src) {
+		destByHash, ok := dest[preimageType]
+		if !ok {
+			destByHash = make(map[common.Hash][]byte, len(byHash))
+			dest[preimageType] = destByHash
+		}
+		for hash, preimage := range byHash {
+			destByHash[hash] = preimage
+		}
+	}
+}
+
+// NewWriterForRPC is generally meant to be only used by nitro.
+// DA Providers should implement the DAProviderService gRPC service independently.
+func NewWriterForRPC(conn *grpc.ClientConn) daprovider.Writer {
+	return &writerForRPC{client: NewDAProviderServiceClient(conn)}
+}
+
+type writerForRPC struct {
+	client DAProviderServiceClient
+}
+
+func (w *writerForRPC) IsValidHeaderByte(headerByte byte) bool {
+	reply, err := w.client.IsValidHeaderByte(context.Background(), &IsValidHeaderByteRequest{HeaderByte: uint32(headerByte)})
+	if err != nil {
+		return false
+	}
+	return reply.IsValid
+}
+
+func (w *writerForRPC) StoreBatch(
+	ctx context.Context,
+	message []byte,
+	timeout time.Duration,
+	disableFallbackStoreDataOnChain bool,
+) ([]byte, error) {
+	stream, err := w.client.StoreBatch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open StoreBatch stream: %w", err)
+	}
+	deadline := time.Now().Add(timeout).Unix()
+	chunks := chunkBytes(message)
+	if len(chunks) == 0 {
+		chunks = [][]byte{nil}
+	}
+	for i, chunk := range chunks {
+		req := &StoreBatchRequest{MessageChunk: chunk}
+		if i == 0 {
+			req.TimeoutUnixSeconds = deadline
+			req.DisableFallbackStoreDataOnChain = disableFallbackStoreDataOnChain
+		}
+		if err := stream.Send(req); err != nil {
+			return nil, fmt.Errorf("failed to send StoreBatch request chunk: %w", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("failed to close StoreBatch send stream: %w", err)
+	}
+	var serializedDACert []byte
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive StoreBatch reply chunk: %w", err)
+		}
+		serializedDACert = append(serializedDACert, reply.SerializedDACertChunk...)
+	}
+	return serializedDACert, nil
+}
+
+func chunkBytes(data []byte) [][]byte {
+	var chunks [][]byte
+	for len(data) > 0 {
+		n := chunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	return chunks
+}
+
+func encodePreimages(preimages map[arbutil.PreimageType]map[common.Hash][]byte) *Preimages {
+	byType := make(map[uint32]*PreimagesForType, len(preimages))
+	for preimageType, byHash := range preimages {
+		encoded := make(map[string][]byte, len(byHash))
+		for hash, preimage := range byHash {
+			encoded[hash.String()] = preimage
+		}
+		byType[uint32(preimageType)] = &PreimagesForType{ByHash: encoded}
+	}
+	return &Preimages{ByType: byType}
+}
+
+func decodePreimages(p *Preimages) map[arbutil.PreimageType]map[common.Hash][]byte {
+	if p == nil {
+		return nil
+	}
+	preimages := make(map[arbutil.PreimageType]map[common.Hash][]byte, len(p.ByType))
+	for preimageType, forType := range p.ByType {
+		byHash := make(map[common.Hash][]byte, len(forType.ByHash))
+		for hashString, preimage := range forType.ByHash {
+			byHash[common.HexToHash(hashString)] = preimage
+		}
+		preimages[arbutil.PreimageType(preimageType)] = byHash
+	}
+	return preimages
+}
@@ -0,0 +1,41 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daclient
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the gRPC content-subtype under which gobCodec is registered. Every call made
+// through this package passes grpc.CallContentSubtype(codecName) so gRPC marshals with gobCodec
+// instead of its default codec, which requires proto.Message and would reject the plain structs
+// in types.go.
+const codecName = "daclient-gob"
+
+func init() {
+	encoding.RegisterCodec(gobCodec{})
+}
+
+// gobCodec implements encoding.Codec for the plain Go structs in types.go using encoding/gob,
+// since those types intentionally don't implement proto.Message.
+type gobCodec struct{}
+
+func (gobCodec) Name() string {
+	return codecName
+}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
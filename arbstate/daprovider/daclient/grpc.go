@@ -0,0 +1,130 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// This file hand-implements the DAProviderServiceClient/Server plumbing that
+// protoc-gen-go-grpc would otherwise generate from daprovider.proto. Every call is pinned to
+// gobCodec (see codec.go) via grpc.CallContentSubtype, since the message types in types.go are
+// plain structs rather than proto.Message implementations.
+package daclient
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	DAProviderService_IsValidHeaderByte_FullMethodName       = "/daclient.DAProviderService/IsValidHeaderByte"
+	DAProviderService_RecoverPayloadFromBatch_FullMethodName = "/daclient.DAProviderService/RecoverPayloadFromBatch"
+	DAProviderService_StoreBatch_FullMethodName              = "/daclient.DAProviderService/StoreBatch"
+)
+
+// DAProviderServiceClient is the client API for DAProviderService.
+type DAProviderServiceClient interface {
+	IsValidHeaderByte(ctx context.Context, in *IsValidHeaderByteRequest, opts ...grpc.CallOption) (*IsValidHeaderByteReply, error)
+	RecoverPayloadFromBatch(ctx context.Context, opts ...grpc.CallOption) (DAProviderService_RecoverPayloadFromBatchClient, error)
+	StoreBatch(ctx context.Context, opts ...grpc.CallOption) (DAProviderService_StoreBatchClient, error)
+}
+
+type daProviderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDAProviderServiceClient wraps cc with the hand-written DAProviderService client.
+func NewDAProviderServiceClient(cc grpc.ClientConnInterface) DAProviderServiceClient {
+	return &daProviderServiceClient{cc}
+}
+
+func (c *daProviderServiceClient) IsValidHeaderByte(ctx context.Context, in *IsValidHeaderByteRequest, opts ...grpc.CallOption) (*IsValidHeaderByteReply, error) {
+	out := new(IsValidHeaderByteReply)
+	opts = append(opts, grpc.CallContentSubtype(codecName))
+	if err := c.cc.Invoke(ctx, DAProviderService_IsValidHeaderByte_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daProviderServiceClient) RecoverPayloadFromBatch(ctx context.Context, opts ...grpc.CallOption) (DAProviderService_RecoverPayloadFromBatchClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(codecName))
+	stream, err := c.cc.NewStream(ctx, &grpc.StreamDesc{StreamName: "RecoverPayloadFromBatch", ServerStreams: true, ClientStreams: true}, DAProviderService_RecoverPayloadFromBatch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &daProviderServiceRecoverPayloadFromBatchClient{stream}, nil
+}
+
+func (c *daProviderServiceClient) StoreBatch(ctx context.Context, opts ...grpc.CallOption) (DAProviderService_StoreBatchClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(codecName))
+	stream, err := c.cc.NewStream(ctx, &grpc.StreamDesc{StreamName: "StoreBatch", ServerStreams: true, ClientStreams: true}, DAProviderService_StoreBatch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &daProviderServiceStoreBatchClient{stream}, nil
+}
+
+type daProviderServiceRecoverPayloadFromBatchClient struct {
+	grpc.ClientStream
+}
+
+func (c *daProviderServiceRecoverPayloadFromBatchClient) Send(req *RecoverPayloadFromBatchRequest) error {
+	return c.ClientStream.SendMsg(req)
+}
+
+func (c *daProviderServiceRecoverPayloadFromBatchClient) Recv() (*RecoverPayloadFromBatchReply, error) {
+	reply := new(RecoverPayloadFromBatchReply)
+	if err := c.ClientStream.RecvMsg(reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+type daProviderServiceStoreBatchClient struct {
+	grpc.ClientStream
+}
+
+func (c *daProviderServiceStoreBatchClient) Send(req *StoreBatchRequest) error {
+	return c.ClientStream.SendMsg(req)
+}
+
+func (c *daProviderServiceStoreBatchClient) Recv() (*StoreBatchReply, error) {
+	reply := new(StoreBatchReply)
+	if err := c.ClientStream.RecvMsg(reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// DAProviderServiceServer is the server API for DAProviderService.
+type DAProviderServiceServer interface {
+	IsValidHeaderByte(ctx context.Context, in *IsValidHeaderByteRequest) (*IsValidHeaderByteReply, error)
+	RecoverPayloadFromBatch(stream DAProviderService_RecoverPayloadFromBatchServer) error
+	StoreBatch(stream DAProviderService_StoreBatchServer) error
+}
+
+// DAProviderService_RecoverPayloadFromBatchClient is the bidirectional stream used to send
+// request chunks and receive reply chunks for RecoverPayloadFromBatch.
+type DAProviderService_RecoverPayloadFromBatchClient interface {
+	Send(*RecoverPayloadFromBatchRequest) error
+	Recv() (*RecoverPayloadFromBatchReply, error)
+	CloseSend() error
+}
+
+// DAProviderService_RecoverPayloadFromBatchServer is the server side of the same stream.
+type DAProviderService_RecoverPayloadFromBatchServer interface {
+	Send(*RecoverPayloadFromBatchReply) error
+	Recv() (*RecoverPayloadFromBatchRequest, error)
+}
+
+// DAProviderService_StoreBatchClient is the bidirectional stream used to send request chunks
+// and receive reply chunks for StoreBatch.
+type DAProviderService_StoreBatchClient interface {
+	Send(*StoreBatchRequest) error
+	Recv() (*StoreBatchReply, error)
+	CloseSend() error
+}
+
+// DAProviderService_StoreBatchServer is the server side of the same stream.
+type DAProviderService_StoreBatchServer interface {
+	Send(*StoreBatchReply) error
+	Recv() (*StoreBatchRequest, error)
+}
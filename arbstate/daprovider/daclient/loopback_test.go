@@ -0,0 +1,105 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type fakeReader struct {
+	payload      []byte
+	preimageHash common.Hash
+	preimage     []byte
+}
+
+func (f *fakeReader) IsValidHeaderByte(headerByte byte) bool {
+	return headerByte == 0x80
+}
+
+func (f *fakeReader) RecoverPayloadFromBatch(
+	_ context.Context,
+	_ uint64,
+	_ common.Hash,
+	_ []byte,
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
+	_ daprovider.KeysetValidationMode,
+) ([]byte, error) {
+	if preimages != nil {
+		if preimages[0] == nil {
+			preimages[0] = make(map[common.Hash][]byte)
+		}
+		preimages[0][f.preimageHash] = f.preimage
+	}
+	return f.payload, nil
+}
+
+type fakeWriter struct {
+	cert []byte
+}
+
+func (f *fakeWriter) IsValidHeaderByte(headerByte byte) bool {
+	return headerByte == 0x80
+}
+
+func (f *fakeWriter) StoreBatch(context.Context, []byte, time.Duration, bool) ([]byte, error) {
+	return f.cert, nil
+}
+
+func TestLoopbackEndToEnd(t *testing.T) {
+	preimageHash := common.HexToHash("0x01")
+	// A large enough payload and message to be split across more than one chunk, so the test
+	// exercises the chunk-reassembly logic rather than just the happy path of a single message.
+	largePayload := make([]byte, 2*chunkSize+17)
+	for i := range largePayload {
+		largePayload[i] = byte(i)
+	}
+	backingReader := &fakeReader{payload: largePayload, preimageHash: preimageHash, preimage: []byte("preimage-value")}
+	backingWriter := &fakeWriter{cert: []byte("serialized-da-cert")}
+
+	reader, writer, stop, err := NewLoopbackReaderAndWriter(backingReader, backingWriter)
+	if err != nil {
+		t.Fatalf("failed to start loopback: %v", err)
+	}
+	defer stop()
+
+	if !reader.IsValidHeaderByte(0x80) {
+		t.Fatalf("expected IsValidHeaderByte(0x80) to be true over RPC")
+	}
+	if reader.IsValidHeaderByte(0x01) {
+		t.Fatalf("expected IsValidHeaderByte(0x01) to be false over RPC")
+	}
+
+	preimages := make(map[arbutil.PreimageType]map[common.Hash][]byte)
+	largeSequencerMsg := make([]byte, 2*chunkSize+9)
+	payload, err := reader.RecoverPayloadFromBatch(context.Background(), 1, common.Hash{}, largeSequencerMsg, preimages, 0)
+	if err != nil {
+		t.Fatalf("RecoverPayloadFromBatch failed: %v", err)
+	}
+	if len(payload) != len(largePayload) {
+		t.Fatalf("expected payload of length %d, got %d", len(largePayload), len(payload))
+	}
+	for i := range payload {
+		if payload[i] != largePayload[i] {
+			t.Fatalf("payload mismatch at byte %d: got %d want %d", i, payload[i], largePayload[i])
+		}
+	}
+	if string(preimages[0][preimageHash]) != "preimage-value" {
+		t.Fatalf("expected preimages reported by the server to be merged into the caller's map, got %v", preimages)
+	}
+
+	cert, err := writer.StoreBatch(context.Background(), []byte("batch message"), time.Minute, false)
+	if err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+	if string(cert) != "serialized-da-cert" {
+		t.Fatalf("expected serialized DA cert %q, got %q", "serialized-da-cert", cert)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// Server adapts a daprovider.Reader and daprovider.Writer to the DAProviderServiceServer gRPC
+// interface, so an out-of-process DA provider binary only has to implement Reader/Writer and
+// can reuse this to expose them over gRPC.
+type Server struct {
+	reader daprovider.Reader
+	writer daprovider.Writer
+}
+
+func NewServer(reader daprovider.Reader, writer daprovider.Writer) *Server {
+	return &Server{reader: reader, writer: writer}
+}
+
+func (s *Server) IsValidHeaderByte(ctx context.Context, in *IsValidHeaderByteRequest) (*IsValidHeaderByteReply, error) {
+	headerByte := byte(in.HeaderByte)
+	isValid := (s.reader != nil && s.reader.IsValidHeaderByte(headerByte)) || (s.writer != nil && s.writer.IsValidHeaderByte(headerByte))
+	return &IsValidHeaderByteReply{IsValid: isValid}, nil
+}
+
+func (s *Server) RecoverPayloadFromBatch(stream DAProviderService_RecoverPayloadFromBatchServer) error {
+	if s.reader == nil {
+		return fmt.Errorf("server was not configured with a reader")
+	}
+	req, sequencerMsg, err := recvRecoverPayloadFromBatchRequest(stream)
+	if err != nil {
+		return err
+	}
+	var batchBlockHash common.Hash
+	copy(batchBlockHash[:], req.BatchBlockHash)
+	preimages := decodePreimages(req.Preimages)
+	if preimages == nil {
+		preimages = make(map[arbutil.PreimageType]map[common.Hash][]byte)
+	}
+	payload, err := s.reader.RecoverPayloadFromBatch(
+		stream.Context(),
+		req.BatchNum,
+		batchBlockHash,
+		sequencerMsg,
+		preimages,
+		daprovider.KeysetValidationMode(req.KeysetValidationMode),
+	)
+	if err != nil {
+		return err
+	}
+	chunks := chunkBytes(payload)
+	if len(chunks) == 0 {
+		chunks = [][]byte{nil}
+	}
+	for i, chunk := range chunks {
+		reply := &RecoverPayloadFromBatchReply{PayloadChunk: chunk}
+		if i == len(chunks)-1 {
+			reply.Preimages = encodePreimages(preimages)
+		}
+		if err := stream.Send(reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recvRecoverPayloadFromBatchRequest(stream DAProviderService_RecoverPayloadFromBatchServer) (*RecoverPayloadFromBatchRequest, []byte, error) {
+	var first *RecoverPayloadFromBatchRequest
+	var sequencerMsg []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to receive RecoverPayloadFromBatch request chunk: %w", err)
+		}
+		if first == nil {
+			first = req
+		}
+		sequencerMsg = append(sequencerMsg, req.SequencerMsgChunk...)
+	}
+	if first == nil {
+		return nil, nil, fmt.Errorf("RecoverPayloadFromBatch stream contained no requests")
+	}
+	return first, sequencerMsg, nil
+}
+
+func (s *Server) StoreBatch(stream DAProviderService_StoreBatchServer) error {
+	if s.writer == nil {
+		return fmt.Errorf("server was not configured with a writer")
+	}
+	var first *StoreBatchRequest
+	var message []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive StoreBatch request chunk: %w", err)
+		}
+		if first == nil {
+			first = req
+		}
+		message = append(message, req.MessageChunk...)
+	}
+	if first == nil {
+		return fmt.Errorf("StoreBatch stream contained no requests")
+	}
+	timeout := time.Until(time.Unix(first.TimeoutUnixSeconds, 0))
+	serializedDACert, err := s.writer.StoreBatch(stream.Context(), message, timeout, first.DisableFallbackStoreDataOnChain)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunkBytes(serializedDACert) {
+		if err := stream.Send(&StoreBatchReply{SerializedDACertChunk: chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
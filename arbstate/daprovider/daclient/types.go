@@ -0,0 +1,55 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// These types mirror the messages declared in daprovider.proto. They are hand-written rather
+// than protoc-generated: the wire codec registered in codec.go marshals them with encoding/gob
+// instead of the protobuf wire format, so they deliberately don't implement proto.Message.
+package daclient
+
+type IsValidHeaderByteRequest struct {
+	HeaderByte uint32
+}
+
+type IsValidHeaderByteReply struct {
+	IsValid bool
+}
+
+// PreimagesForType mirrors map[common.Hash][]byte for a single arbutil.PreimageType.
+type PreimagesForType struct {
+	ByHash map[string][]byte
+}
+
+// Preimages mirrors map[arbutil.PreimageType]map[common.Hash][]byte.
+type Preimages struct {
+	ByType map[uint32]*PreimagesForType
+}
+
+// RecoverPayloadFromBatchRequest is one chunk of a RecoverPayloadFromBatch request stream. See
+// daprovider.proto for the chunking contract.
+type RecoverPayloadFromBatchRequest struct {
+	BatchNum             uint64
+	BatchBlockHash       []byte
+	SequencerMsgChunk    []byte
+	Preimages            *Preimages
+	KeysetValidationMode uint32
+}
+
+// RecoverPayloadFromBatchReply is one chunk of a RecoverPayloadFromBatch reply stream.
+// Preimages is only populated on the last reply message (once the server has the complete
+// picture of what the recovery touched); earlier messages leave it nil.
+type RecoverPayloadFromBatchReply struct {
+	PayloadChunk []byte
+	Preimages    *Preimages
+}
+
+// StoreBatchRequest is one chunk of a StoreBatch request stream.
+type StoreBatchRequest struct {
+	MessageChunk                    []byte
+	TimeoutUnixSeconds              int64
+	DisableFallbackStoreDataOnChain bool
+}
+
+// StoreBatchReply is one chunk of a StoreBatch reply stream.
+type StoreBatchReply struct {
+	SerializedDACertChunk []byte
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// MultiReader dispatches RecoverPayloadFromBatch to the first registered Reader whose
+// IsValidHeaderByte matches the batch's header byte. It lets a node handle batches from
+// multiple DA layers (e.g. EIP-4844 blobs, AnyTrust DAS, and third-party providers) without the
+// caller having to hand-wire each reader itself.
+type MultiReader struct {
+	registrations []multiReaderRegistration
+}
+
+type multiReaderRegistration struct {
+	headerByteMask byte
+	reader         Reader
+}
+
+// NewMultiReader is generally meant to be only used by nitro.
+func NewMultiReader() *MultiReader {
+	return &MultiReader{}
+}
+
+// Register adds a Reader to the MultiReader. headerByteMask is recorded purely for the "no
+// reader matched" error message; dispatch itself is decided by each reader's IsValidHeaderByte.
+func (m *MultiReader) Register(headerByteMask byte, reader Reader) {
+	m.registrations = append(m.registrations, multiReaderRegistration{headerByteMask, reader})
+}
+
+func (m *MultiReader) IsValidHeaderByte(headerByte byte) bool {
+	for _, reg := range m.registrations {
+		if reg.reader.IsValidHeaderByte(headerByte) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MultiReader) RecoverPayloadFromBatch(
+	ctx context.Context,
+	batchNum uint64,
+	batchBlockHash common.Hash,
+	sequencerMsg []byte,
+	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
+	keysetValidationMode KeysetValidationMode,
+) ([]byte, error) {
+	if len(sequencerMsg) <= 40 {
+		return nil, fmt.Errorf("sequencer message is too short to contain a header byte")
+	}
+	headerByte := sequencerMsg[40]
+	for _, reg := range m.registrations {
+		if reg.reader.IsValidHeaderByte(headerByte) {
+			return reg.reader.RecoverPayloadFromBatch(ctx, batchNum, batchBlockHash, sequencerMsg, preimages, keysetValidationMode)
+		}
+	}
+	return nil, fmt.Errorf("no registered reader matches header byte %#x (registered header bytes: %v)", headerByte, m.registeredHeaderBytes())
+}
+
+func (m *MultiReader) registeredHeaderBytes() []byte {
+	headerBytes := make([]byte, len(m.registrations))
+	for i, reg := range m.registrations {
+		headerBytes[i] = reg.headerByteMask
+	}
+	return headerBytes
+}
@@ -0,0 +1,72 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type fakeReader struct {
+	validHeaderByte byte
+	payload         []byte
+}
+
+func (f *fakeReader) IsValidHeaderByte(headerByte byte) bool {
+	return headerByte&f.validHeaderByte != 0
+}
+
+func (f *fakeReader) RecoverPayloadFromBatch(
+	context.Context,
+	uint64,
+	common.Hash,
+	[]byte,
+	map[arbutil.PreimageType]map[common.Hash][]byte,
+	KeysetValidationMode,
+) ([]byte, error) {
+	return f.payload, nil
+}
+
+func sequencerMsgWithHeaderByte(headerByte byte) []byte {
+	msg := make([]byte, 41)
+	msg[40] = headerByte
+	return msg
+}
+
+func TestMultiReaderDispatchesToMatchingProvider(t *testing.T) {
+	dasReader := &fakeReader{validHeaderByte: 0x80, payload: []byte("das")}
+	blobReader := &fakeReader{validHeaderByte: 0x40, payload: []byte("blob")}
+	multi := NewMultiReader()
+	multi.Register(0x80, dasReader)
+	multi.Register(0x40, blobReader)
+
+	payload, err := multi.RecoverPayloadFromBatch(context.Background(), 0, common.Hash{}, sequencerMsgWithHeaderByte(0x80), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != "das" {
+		t.Fatalf("expected dasReader's payload, got %q", payload)
+	}
+
+	payload, err = multi.RecoverPayloadFromBatch(context.Background(), 0, common.Hash{}, sequencerMsgWithHeaderByte(0x40), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != "blob" {
+		t.Fatalf("expected blobReader's payload, got %q", payload)
+	}
+}
+
+func TestMultiReaderErrorsWhenNoProviderMatches(t *testing.T) {
+	multi := NewMultiReader()
+	multi.Register(0x80, &fakeReader{validHeaderByte: 0x80})
+
+	_, err := multi.RecoverPayloadFromBatch(context.Background(), 0, common.Hash{}, sequencerMsgWithHeaderByte(0x01), nil, 0)
+	if err == nil {
+		t.Fatalf("expected an error when no registered reader matches the header byte")
+	}
+}
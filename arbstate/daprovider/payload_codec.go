@@ -0,0 +1,132 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// PayloadCodec compresses and decompresses payloads recovered from RecoverPayloadFromBatch, so
+// DA providers can shrink the data they post without changing the batch framing itself.
+type PayloadCodec interface {
+	Encode(payload []byte) ([]byte, error)
+	Decode(encoded []byte, maxSize int) ([]byte, error)
+}
+
+// payloadCodecByte is the header byte a DA provider prefixes a payload with to indicate which
+// PayloadCodec was used to compress it.
+type payloadCodecByte byte
+
+const (
+	RawPayloadCodecByte    payloadCodecByte = 0x00
+	GzipPayloadCodecByte   payloadCodecByte = 0x01
+	BrotliPayloadCodecByte payloadCodecByte = 0x02
+)
+
+var payloadCodecs = map[payloadCodecByte]PayloadCodec{
+	RawPayloadCodecByte:    rawPayloadCodec{},
+	GzipPayloadCodecByte:   gzipPayloadCodec{},
+	BrotliPayloadCodecByte: brotliPayloadCodec{},
+}
+
+// DecodeCodecPayload reads the codec byte from the front of encoded and decodes the remainder
+// with the matching PayloadCodec, enforcing maxSize on the decompressed result to protect
+// against a malicious DA provider returning a zip bomb.
+func DecodeCodecPayload(encoded []byte, maxSize int) ([]byte, error) {
+	if len(encoded) < 1 {
+		return nil, fmt.Errorf("payload is too short to contain a codec byte")
+	}
+	codec, ok := payloadCodecs[payloadCodecByte(encoded[0])]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload codec byte %#x", encoded[0])
+	}
+	return codec.Decode(encoded[1:], maxSize)
+}
+
+// EncodeCodecPayload compresses payload with the PayloadCodec registered for codecByte and
+// prefixes the result with that codec byte.
+func EncodeCodecPayload(codecByte payloadCodecByte, payload []byte) ([]byte, error) {
+	codec, ok := payloadCodecs[codecByte]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload codec byte %#x", codecByte)
+	}
+	encoded, err := codec.Encode(payload)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(codecByte)}, encoded...), nil
+}
+
+type rawPayloadCodec struct{}
+
+func (rawPayloadCodec) Encode(payload []byte) ([]byte, error) {
+	return payload, nil
+}
+
+func (rawPayloadCodec) Decode(encoded []byte, maxSize int) ([]byte, error) {
+	if len(encoded) > maxSize {
+		return nil, fmt.Errorf("payload size %v exceeds max size %v", len(encoded), maxSize)
+	}
+	return encoded, nil
+}
+
+type gzipPayloadCodec struct{}
+
+func (gzipPayloadCodec) Encode(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipPayloadCodec) Decode(encoded []byte, maxSize int) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer reader.Close()
+	return readWithSizeCap(reader, maxSize)
+}
+
+type brotliPayloadCodec struct{}
+
+func (brotliPayloadCodec) Encode(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := brotli.NewWriter(&buf)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (brotliPayloadCodec) Decode(encoded []byte, maxSize int) ([]byte, error) {
+	return readWithSizeCap(brotli.NewReader(bytes.NewReader(encoded)), maxSize)
+}
+
+// readWithSizeCap reads at most maxSize+1 bytes from r and errors if more than maxSize bytes
+// were available, so a malicious DA provider cannot force unbounded decompression.
+func readWithSizeCap(r io.Reader, maxSize int) ([]byte, error) {
+	limited := io.LimitReader(r, int64(maxSize)+1)
+	decoded, err := io.ReadAll(limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	if len(decoded) > maxSize {
+		return nil, fmt.Errorf("decompressed payload exceeds max size %v", maxSize)
+	}
+	return decoded, nil
+}
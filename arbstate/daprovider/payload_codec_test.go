@@ -0,0 +1,45 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPayloadCodecRoundTrip(t *testing.T) {
+	payload := []byte("the quick brown fox jumps over the lazy dog, repeated for compressibility. " +
+		"the quick brown fox jumps over the lazy dog, repeated for compressibility.")
+
+	for _, codecByte := range []payloadCodecByte{RawPayloadCodecByte, GzipPayloadCodecByte, BrotliPayloadCodecByte} {
+		encoded, err := EncodeCodecPayload(codecByte, payload)
+		if err != nil {
+			t.Fatalf("codec %#x: failed to encode: %v", codecByte, err)
+		}
+		decoded, err := DecodeCodecPayload(encoded, len(payload))
+		if err != nil {
+			t.Fatalf("codec %#x: failed to decode: %v", codecByte, err)
+		}
+		if !bytes.Equal(decoded, payload) {
+			t.Fatalf("codec %#x: round trip mismatch: got %q, want %q", codecByte, decoded, payload)
+		}
+	}
+}
+
+func TestPayloadCodecEnforcesMaxSize(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 1024)
+	encoded, err := EncodeCodecPayload(GzipPayloadCodecByte, payload)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if _, err := DecodeCodecPayload(encoded, len(payload)-1); err == nil {
+		t.Fatalf("expected decompression exceeding maxSize to be rejected")
+	}
+}
+
+func TestPayloadCodecRejectsUnknownCodecByte(t *testing.T) {
+	if _, err := DecodeCodecPayload([]byte{0xff, 1, 2, 3}, 1024); err == nil {
+		t.Fatalf("expected an unknown codec byte to be rejected")
+	}
+}
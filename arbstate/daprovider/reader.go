@@ -5,10 +5,13 @@ package daprovider
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/util/blobs"
 )
@@ -28,14 +31,22 @@ type Reader interface {
 	) ([]byte, error)
 }
 
+// defaultMaxRecoveredPayloadSize bounds the decompressed size of a codec-wrapped payload
+// recovered from a DA provider, so a malicious or misbehaving provider can't zip-bomb a reader.
+const defaultMaxRecoveredPayloadSize = 10 * 1024 * 1024
+
 // NewReaderForDAS is generally meant to be only used by nitro.
 // DA Providers should implement methods in the Reader interface independently
 func NewReaderForDAS(dasReader DASReader) *readerForDAS {
-	return &readerForDAS{dasReader: dasReader}
+	return &readerForDAS{dasReader: dasReader, MaxRecoveredPayloadSize: defaultMaxRecoveredPayloadSize}
 }
 
 type readerForDAS struct {
 	dasReader DASReader
+
+	// MaxRecoveredPayloadSize caps the decompressed size of a codec-wrapped payload recovered
+	// from the DAS backend.
+	MaxRecoveredPayloadSize int
 }
 
 func (d *readerForDAS) IsValidHeaderByte(headerByte byte) bool {
@@ -50,17 +61,36 @@ func (d *readerForDAS) RecoverPayloadFromBatch(
 	preimages map[arbutil.PreimageType]map[common.Hash][]byte,
 	keysetValidationMode KeysetValidationMode,
 ) ([]byte, error) {
-	return RecoverPayloadFromDasBatch(ctx, batchNum, sequencerMsg, d.dasReader, preimages, keysetValidationMode)
+	payload, err := RecoverPayloadFromDasBatch(ctx, batchNum, sequencerMsg, d.dasReader, preimages, keysetValidationMode)
+	if err != nil || payload == nil {
+		return payload, err
+	}
+	return DecodeCodecPayload(payload, d.MaxRecoveredPayloadSize)
 }
 
 // NewReaderForBlobReader is generally meant to be only used by nitro.
 // DA Providers should implement methods in the Reader interface independently
 func NewReaderForBlobReader(blobReader BlobReader) *readerForBlobReader {
-	return &readerForBlobReader{blobReader: blobReader}
+	return &readerForBlobReader{
+		blobReader:              blobReader,
+		StrictKZGVerification:   true,
+		MaxRecoveredPayloadSize: defaultMaxRecoveredPayloadSize,
+	}
 }
 
 type readerForBlobReader struct {
 	blobReader BlobReader
+
+	// StrictKZGVerification, when true, recomputes the KZG commitment for each blob returned by
+	// the blob reader and checks it against the versioned hash from the sequencer message before
+	// decoding. It should only be disabled when the blob source itself is trusted (e.g. a local
+	// beacon node), since skipping this check allows an untrusted blob source to substitute
+	// arbitrary blob data.
+	StrictKZGVerification bool
+
+	// MaxRecoveredPayloadSize caps the decompressed size of a codec-wrapped payload recovered
+	// from the blob backend.
+	MaxRecoveredPayloadSize int
 }
 
 func (b *readerForBlobReader) IsValidHeaderByte(headerByte byte) bool {
@@ -87,10 +117,40 @@ func (b *readerForBlobReader) RecoverPayloadFromBatch(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blobs: %w", err)
 	}
+	if b.StrictKZGVerification {
+		if err := verifyBlobsAgainstVersionedHashes(kzgBlobs, versionedHashes); err != nil {
+			return nil, err
+		}
+	}
 	payload, err := blobs.DecodeBlobs(kzgBlobs)
 	if err != nil {
 		log.Warn("Failed to decode blobs", "batchBlockHash", batchBlockHash, "versionedHashes", versionedHashes, "err", err)
 		return nil, nil
 	}
-	return payload, nil
+	return DecodeCodecPayload(payload, b.MaxRecoveredPayloadSize)
+}
+
+// verifyBlobsAgainstVersionedHashes checks that each blob's KZG commitment hashes to the
+// corresponding versioned hash, so a malicious or misconfigured blob source cannot substitute
+// blob data that was never actually posted on chain.
+func verifyBlobsAgainstVersionedHashes(kzgBlobs []kzg4844.Blob, versionedHashes []common.Hash) error {
+	if len(kzgBlobs) != len(versionedHashes) {
+		return fmt.Errorf("got %v blobs but expected %v versioned hashes", len(kzgBlobs), len(versionedHashes))
+	}
+	for i, blob := range kzgBlobs {
+		commitment, err := kzg4844.BlobToCommitment(&blob)
+		if err != nil {
+			return fmt.Errorf("failed to compute KZG commitment for blob %v: %w", i, err)
+		}
+		if computeVersionedHash(commitment) != versionedHashes[i] {
+			return fmt.Errorf("blob %v does not match its versioned hash %v", i, versionedHashes[i])
+		}
+	}
+	return nil
+}
+
+func computeVersionedHash(commitment kzg4844.Commitment) common.Hash {
+	hash := sha256.Sum256(commitment[:])
+	hash[0] = params.BlobTxHashVersion
+	return hash
 }
@@ -0,0 +1,51 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func versionedHashForBlob(t *testing.T, blob kzg4844.Blob) common.Hash {
+	t.Helper()
+	commitment, err := kzg4844.BlobToCommitment(&blob)
+	if err != nil {
+		t.Fatalf("failed to compute commitment: %v", err)
+	}
+	hash := sha256.Sum256(commitment[:])
+	hash[0] = params.BlobTxHashVersion
+	return hash
+}
+
+func TestVerifyBlobsAgainstVersionedHashesAccepted(t *testing.T) {
+	var blob kzg4844.Blob
+	copy(blob[:], []byte("hello world"))
+	versionedHash := versionedHashForBlob(t, blob)
+
+	if err := verifyBlobsAgainstVersionedHashes([]kzg4844.Blob{blob}, []common.Hash{versionedHash}); err != nil {
+		t.Fatalf("expected matching blob/versioned-hash pair to verify, got: %v", err)
+	}
+}
+
+func TestVerifyBlobsAgainstVersionedHashesRejectsMismatch(t *testing.T) {
+	var blob kzg4844.Blob
+	copy(blob[:], []byte("hello world"))
+	var wrongVersionedHash common.Hash
+	copy(wrongVersionedHash[:], []byte("not the right hash"))
+
+	if err := verifyBlobsAgainstVersionedHashes([]kzg4844.Blob{blob}, []common.Hash{wrongVersionedHash}); err == nil {
+		t.Fatalf("expected a blob that doesn't match its versioned hash to be rejected")
+	}
+}
+
+func TestVerifyBlobsAgainstVersionedHashesRejectsCountMismatch(t *testing.T) {
+	if err := verifyBlobsAgainstVersionedHashes(nil, []common.Hash{{}}); err == nil {
+		t.Fatalf("expected a blob/versioned-hash count mismatch to be rejected")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Writer interface {
+	// IsValidHeaderByte returns true if the given headerByte has bits corresponding to the DA provider
+	IsValidHeaderByte(headerByte byte) bool
+
+	// StoreBatch posts the batch data to the DA provider and returns the serialized DA cert that
+	// should be included in the sequencer message in place of the raw batch data
+	StoreBatch(
+		ctx context.Context,
+		message []byte,
+		timeout time.Duration,
+		disableFallbackStoreDataOnChain bool,
+	) (serializedDACert []byte, err error)
+}
+
+// NewWriterForDAS is generally meant to be only used by nitro.
+// DA Providers should implement methods in the Writer interface independently
+func NewWriterForDAS(dasWriter DASWriter) *writerForDAS {
+	return &writerForDAS{dasWriter: dasWriter, PayloadCodecByte: RawPayloadCodecByte}
+}
+
+type writerForDAS struct {
+	dasWriter DASWriter
+
+	// PayloadCodecByte selects the PayloadCodec used to wrap the message before it's posted to
+	// the DAS backend. It defaults to RawPayloadCodecByte (no compression); set it to
+	// GzipPayloadCodecByte or BrotliPayloadCodecByte to compress batches in flight. Whatever is
+	// chosen here must always pair with readerForDAS.RecoverPayloadFromBatch's unconditional
+	// DecodeCodecPayload call, so the codec byte is prefixed here rather than left optional.
+	PayloadCodecByte payloadCodecByte
+}
+
+func (d *writerForDAS) IsValidHeaderByte(headerByte byte) bool {
+	return IsDASMessageHeaderByte(headerByte)
+}
+
+func (d *writerForDAS) StoreBatch(
+	ctx context.Context,
+	message []byte,
+	timeout time.Duration,
+	disableFallbackStoreDataOnChain bool,
+) ([]byte, error) {
+	encoded, err := EncodeCodecPayload(d.PayloadCodecByte, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload for DAS: %w", err)
+	}
+	return d.dasWriter.Store(ctx, encoded, timeout, disableFallbackStoreDataOnChain)
+}
+
+// NewWriterForBlobReader is generally meant to be only used by nitro.
+// DA Providers should implement methods in the Writer interface independently
+func NewWriterForBlobReader(blobWriter BlobWriter) *writerForBlobReader {
+	return &writerForBlobReader{blobWriter: blobWriter, PayloadCodecByte: RawPayloadCodecByte}
+}
+
+type writerForBlobReader struct {
+	blobWriter BlobWriter
+
+	// PayloadCodecByte selects the PayloadCodec used to wrap the message before it's split into
+	// blobs. It defaults to RawPayloadCodecByte; see writerForDAS.PayloadCodecByte for why this
+	// must stay paired with readerForBlobReader.RecoverPayloadFromBatch's decode call.
+	PayloadCodecByte payloadCodecByte
+}
+
+func (b *writerForBlobReader) IsValidHeaderByte(headerByte byte) bool {
+	return IsBlobHashesHeaderByte(headerByte)
+}
+
+func (b *writerForBlobReader) StoreBatch(
+	ctx context.Context,
+	message []byte,
+	timeout time.Duration,
+	disableFallbackStoreDataOnChain bool,
+) ([]byte, error) {
+	encoded, err := EncodeCodecPayload(b.PayloadCodecByte, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload for blob batch: %w", err)
+	}
+	return b.blobWriter.StoreBlobs(ctx, encoded, timeout, disableFallbackStoreDataOnChain)
+}
+
+// WriterRegistry lets a node register multiple Writer implementations and route StoreBatch
+// calls to the one whose header byte matches the caller's preferred DA target. Like Reader and
+// MultiReader, dispatch goes through IsValidHeaderByte rather than exact-byte equality, since
+// header bytes in this system are bit flags that combine (e.g. a DAS bit alongside a brotli
+// bit), not single enum values.
+type WriterRegistry struct {
+	registrations []writerRegistration
+}
+
+type writerRegistration struct {
+	headerByte byte
+	writer     Writer
+}
+
+func NewWriterRegistry() *WriterRegistry {
+	return &WriterRegistry{}
+}
+
+// Register adds a Writer to the registry. headerByte is recorded purely for the "no writer
+// matched" error message; dispatch itself is decided by the writer's own IsValidHeaderByte.
+func (r *WriterRegistry) Register(headerByte byte, writer Writer) {
+	r.registrations = append(r.registrations, writerRegistration{headerByte, writer})
+}
+
+// WriterByHeaderByte returns the first registered Writer whose IsValidHeaderByte matches
+// headerByte.
+func (r *WriterRegistry) WriterByHeaderByte(headerByte byte) (Writer, error) {
+	for _, reg := range r.registrations {
+		if reg.writer.IsValidHeaderByte(headerByte) {
+			return reg.writer, nil
+		}
+	}
+	return nil, fmt.Errorf("no writer registered for header byte %#x (registered header bytes: % #x)", headerByte, r.registeredHeaderBytes())
+}
+
+func (r *WriterRegistry) registeredHeaderBytes() []byte {
+	headerBytes := make([]byte, len(r.registrations))
+	for i, reg := range r.registrations {
+		headerBytes[i] = reg.headerByte
+	}
+	return headerBytes
+}
@@ -0,0 +1,78 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package daprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeWriter struct {
+	validHeaderByte byte
+}
+
+func (f *fakeWriter) IsValidHeaderByte(headerByte byte) bool {
+	return headerByte&f.validHeaderByte != 0
+}
+
+func (f *fakeWriter) StoreBatch(context.Context, []byte, time.Duration, bool) ([]byte, error) {
+	return []byte("cert"), nil
+}
+
+type fakeDASWriter struct {
+	stored []byte
+}
+
+func (f *fakeDASWriter) Store(_ context.Context, message []byte, _ time.Duration, _ bool) ([]byte, error) {
+	f.stored = message
+	return []byte("cert"), nil
+}
+
+// TestWriterForDASStoreBatchPairsWithDecodeCodecPayload exercises the real StoreBatch path
+// rather than just EncodeCodecPayload/DecodeCodecPayload in isolation, so a regression that
+// breaks the encode/decode pairing (e.g. StoreBatch forgetting to encode) shows up here.
+func TestWriterForDASStoreBatchPairsWithDecodeCodecPayload(t *testing.T) {
+	dasWriter := &fakeDASWriter{}
+	writer := NewWriterForDAS(dasWriter)
+	message := []byte("batch message posted through the DAS writer")
+
+	if _, err := writer.StoreBatch(context.Background(), message, time.Minute, false); err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+
+	decoded, err := DecodeCodecPayload(dasWriter.stored, len(message))
+	if err != nil {
+		t.Fatalf("failed to decode what StoreBatch stored: %v", err)
+	}
+	if string(decoded) != string(message) {
+		t.Fatalf("expected decoded payload %q, got %q", message, decoded)
+	}
+}
+
+func TestWriterRegistryDispatchesByPredicateNotExactByte(t *testing.T) {
+	registry := NewWriterRegistry()
+	dasWriter := &fakeWriter{validHeaderByte: 0x80}
+	registry.Register(0x80, dasWriter)
+
+	// A real sequencer message header byte often carries extra flag bits (e.g. a brotli bit)
+	// alongside the DAS bit. The registry must still find dasWriter for it.
+	headerByteWithExtraFlags := byte(0x80 | 0x10)
+	writer, err := registry.WriterByHeaderByte(headerByteWithExtraFlags)
+	if err != nil {
+		t.Fatalf("expected a writer to match header byte %#x, got error: %v", headerByteWithExtraFlags, err)
+	}
+	if writer != dasWriter {
+		t.Fatalf("expected dasWriter to be returned")
+	}
+
+	_, err = registry.WriterByHeaderByte(0x01)
+	if err == nil {
+		t.Fatalf("expected no writer to match header byte 0x01")
+	}
+	if !strings.Contains(err.Error(), "0x80") {
+		t.Fatalf("expected the error to list the registered header byte 0x80, got: %v", err)
+	}
+}